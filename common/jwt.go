@@ -0,0 +1,136 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenClaims are the claims embedded in a signed token, modeled on
+// Supabase auth's access token payload.
+type TokenClaims struct {
+	Subject     int    `json:"sub"`
+	TokenId     int    `json:"token_id"`
+	Group       string `json:"group"`
+	RemainQuota int    `json:"remain_quota"`
+	Issuer      string `json:"iss"`
+	Audience    string `json:"aud"`
+	jwt.RegisteredClaims
+}
+
+// BuildTokenClaims assembles the standard claim set for a token before it
+// is optionally passed through the CustomAccessTokenHook and signed.
+func BuildTokenClaims(userId, tokenId int, group string, remainQuota int, expiredTime int64, issuer, audience string) *TokenClaims {
+	claims := &TokenClaims{
+		Subject:     userId,
+		TokenId:     tokenId,
+		Group:       group,
+		RemainQuota: remainQuota,
+		Issuer:      issuer,
+		Audience:    audience,
+	}
+	claims.RegisteredClaims.Issuer = issuer
+	claims.RegisteredClaims.Audience = jwt.ClaimStrings{audience}
+	if expiredTime > 0 {
+		claims.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(time.Unix(expiredTime, 0))
+	}
+	return claims
+}
+
+// InvokeCustomAccessTokenHook posts the pre-sign claims to hookURL and
+// returns the claims object the hook responds with, in place of the
+// original (the original is returned unchanged when hookURL is empty).
+// This mirrors Supabase's custom access token hook contract so
+// downstream systems can embed extra fields (tenant_id, department, model
+// allowlist, ...) directly in the token.
+func InvokeCustomAccessTokenHook(hookURL string, claims map[string]interface{}) (map[string]interface{}, error) {
+	if hookURL == "" {
+		return claims, nil
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Post(hookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("custom access token hook returned non-200 status")
+	}
+	var modified map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&modified); err != nil {
+		return nil, err
+	}
+	return modified, nil
+}
+
+// SignJWT signs claims with algorithm ("HS256" or "RS256") using key,
+// which is the raw HMAC secret for HS256 or a PEM-encoded RSA private
+// key for RS256.
+func SignJWT(claims jwt.Claims, algorithm, key string) (string, error) {
+	if key == "" {
+		return "", errors.New("JWT signing key is not configured")
+	}
+	switch algorithm {
+	case "HS256":
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(key))
+	case "RS256":
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key))
+		if err != nil {
+			return "", err
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		return token.SignedString(privateKey)
+	default:
+		return "", errors.New("unsupported JWT algorithm: " + algorithm)
+	}
+}
+
+// ParseJWT verifies a signed token and returns its claims. key is the
+// verification key for algorithm: the same shared secret SignJWT used
+// for HS256, or the RS256 public key PEM — never the RS256 private key,
+// which cannot be parsed by ParseRSAPublicKeyFromPEM.
+func ParseJWT(tokenString, algorithm, key string) (*TokenClaims, error) {
+	if key == "" {
+		return nil, errors.New("JWT verification key is not configured")
+	}
+	claims := &TokenClaims{}
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		switch algorithm {
+		case "HS256":
+			return []byte(key), nil
+		case "RS256":
+			return jwt.ParseRSAPublicKeyFromPEM([]byte(key))
+		default:
+			return nil, errors.New("unsupported JWT algorithm: " + algorithm)
+		}
+	}
+	token, err := jwt.ParseWithClaims(tokenString, claims, keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// LooksLikeJWT reports whether s has the three dot-separated segments a
+// compact JWT uses, so the relay middleware can decide whether to try
+// JWT verification before falling back to the opaque-key lookup.
+func LooksLikeJWT(s string) bool {
+	dots := 0
+	for _, r := range s {
+		if r == '.' {
+			dots++
+		}
+	}
+	return dots == 2
+}
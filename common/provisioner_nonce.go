@@ -0,0 +1,26 @@
+package common
+
+import (
+	"context"
+	"time"
+)
+
+// ClaimProvisionerNonce records keyId+nonce in Redis with a TTL matching
+// the allowed clock skew and reports whether this is the first time it
+// has been seen. A second claim within the window means the request is
+// a replay and should be rejected.
+func ClaimProvisionerNonce(keyId, nonce string, window time.Duration) bool {
+	if !RedisEnabled {
+		// Without Redis there is nowhere durable to track nonces across
+		// instances; fail open rather than reject every request, since
+		// the timestamp skew check still bounds the replay window.
+		return true
+	}
+	key := "provisioner_nonce:" + keyId + ":" + nonce
+	set, err := RDB.SetNX(context.Background(), key, 1, window).Result()
+	if err != nil {
+		SysError("failed to claim provisioner nonce: " + err.Error())
+		return false
+	}
+	return set
+}
@@ -1,11 +1,15 @@
 package controller
 
 import (
+	"encoding/json"
 	"net/http"
 	"one-api/common"
 	"one-api/model"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 // AutoCreateTokenRequest defines the request structure for auto token creation
@@ -16,6 +20,8 @@ type AutoCreateTokenRequest struct {
 	RemainQuota int    `json:"remain_quota"`  // Initial quota for the token
 	ExpiredTime int64  `json:"expired_time"`  // Expiration time (-1 for never expire)
 	Group       string `json:"group"`         // Group for the token (optional)
+	QuotaGroup  string `json:"quota_group"`   // Name of a QuotaGroup to attach instead of a raw RemainQuota (optional)
+	TokenType   string `json:"token_type"`    // "opaque" (default) or "jwt"
 }
 
 // AutoCreateTokenResponse defines the response structure
@@ -26,6 +32,7 @@ type AutoCreateTokenResponse struct {
 		TokenID int    `json:"token_id"`
 		Key     string `json:"key"`
 		UserID  int    `json:"user_id"`
+		JWT     string `json:"jwt,omitempty"` // Signed JWT, present when token_type is "jwt"
 	} `json:"data,omitempty"`
 }
 
@@ -71,6 +78,23 @@ func AutoCreateToken(c *gin.Context) {
 		return
 	}
 
+	// Resolve the quota group (if any) before applying the RemainQuota
+	// default so a group's total_quota rule takes precedence.
+	var quotaGroup *model.QuotaGroup
+	if req.QuotaGroup != "" {
+		quotaGroup, err = model.GetQuotaGroupByName(req.QuotaGroup)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, AutoCreateTokenResponse{
+				Success: false,
+				Message: "Invalid quota_group: " + err.Error(),
+			})
+			return
+		}
+		if req.RemainQuota <= 0 {
+			req.RemainQuota = quotaGroupTotalQuota(quotaGroup)
+		}
+	}
+
 	// Set default values
 	if req.RemainQuota <= 0 {
 		req.RemainQuota = 100000 // Default quota: 100,000
@@ -140,6 +164,14 @@ func AutoCreateToken(c *gin.Context) {
 		return
 	}
 
+	// Attach the resolved quota group, if any, to the new token
+	if quotaGroup != nil {
+		err = model.AttachQuotaGroup(quotaGroup.Id, 0, token.Id)
+		if err != nil {
+			common.SysError("failed to attach quota group to token: " + err.Error())
+		}
+	}
+
 	// Return success response with token info
 	response := AutoCreateTokenResponse{
 		Success: true,
@@ -149,6 +181,18 @@ func AutoCreateToken(c *gin.Context) {
 	response.Data.Key = key
 	response.Data.UserID = user.Id
 
+	if req.TokenType == "jwt" {
+		signedToken, err := signJWTForToken(&token, user.Id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, AutoCreateTokenResponse{
+				Success: false,
+				Message: "Failed to sign JWT: " + err.Error(),
+			})
+			return
+		}
+		response.Data.JWT = signedToken
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -411,4 +455,54 @@ func AddTokenQuota(c *gin.Context) {
 		Success: true,
 		Message: "Token quota added successfully",
 	})
+}
+
+// signJWTForToken builds the standard claim set for token, runs it through
+// the CustomAccessTokenHook if one is configured, and signs the result
+// using the system's JWTSigningKey/JWTAlgorithm.
+func signJWTForToken(token *model.Token, userId int) (string, error) {
+	claims := common.BuildTokenClaims(userId, token.Id, token.Group, token.RemainQuota, token.ExpiredTime, model.JWTIssuer, model.JWTAudience)
+
+	if model.CustomAccessTokenHookURL != "" {
+		raw, err := json.Marshal(claims)
+		if err != nil {
+			return "", err
+		}
+		var asMap map[string]interface{}
+		if err := json.Unmarshal(raw, &asMap); err != nil {
+			return "", err
+		}
+		modified, err := common.InvokeCustomAccessTokenHook(model.CustomAccessTokenHookURL, asMap)
+		if err != nil {
+			common.SysError("custom access token hook failed, using original claims: " + err.Error())
+		} else {
+			// Sign the hook's claims object as returned, so extra fields
+			// that TokenClaims has no slot for are kept in the token.
+			return common.SignJWT(jwt.MapClaims(modified), model.JWTAlgorithm, model.JWTSigningKey)
+		}
+	}
+
+	return common.SignJWT(claims, model.JWTAlgorithm, model.JWTSigningKey)
+}
+
+// quotaGroupTotalQuota returns the limit of the group's total_quota rule,
+// or 0 if the group has none.
+func quotaGroupTotalQuota(group *model.QuotaGroup) int {
+	rules, err := model.GetAllQuotaRules()
+	if err != nil {
+		return 0
+	}
+	ruleIds := make(map[int]bool)
+	for _, idStr := range strings.Split(group.RuleIds, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(idStr))
+		if err == nil {
+			ruleIds[id] = true
+		}
+	}
+	for _, rule := range rules {
+		if ruleIds[rule.Id] && rule.Subject == model.QuotaSubjectTotalQuota {
+			return int(rule.Limit)
+		}
+	}
+	return 0
 } 
\ No newline at end of file
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"one-api/common"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BulkCreateTokenItem describes one token to provision in a
+// BulkCreateTokens call.
+type BulkCreateTokenItem struct {
+	Username    string `json:"username"`
+	TokenName   string `json:"token_name"`
+	RemainQuota int    `json:"remain_quota"`
+	Group       string `json:"group"`
+	ExpiredTime int64  `json:"expired_time"`
+	QuotaGroup  string `json:"quota_group"`
+}
+
+// BulkCreateTokenResult reports the outcome of one item in the batch.
+type BulkCreateTokenResult struct {
+	Username string `json:"username"`
+	Success  bool   `json:"success"`
+	Message  string `json:"message"`
+	TokenID  int    `json:"token_id,omitempty"`
+	Key      string `json:"key,omitempty"`
+}
+
+// BulkCreateTokensRequest defines the request body for BulkCreateTokens.
+type BulkCreateTokensRequest struct {
+	Tokens []BulkCreateTokenItem `json:"tokens"`
+}
+
+// BulkCreateTokensResponse defines the response body for BulkCreateTokens.
+type BulkCreateTokensResponse struct {
+	Success bool                     `json:"success"`
+	Message string                   `json:"message"`
+	Results []BulkCreateTokenResult  `json:"results"`
+}
+
+// BulkCreateTokens seeds many tokens in one call and one transaction,
+// reporting per-item success/failure. If any item fails, the whole
+// batch is rolled back and no token is created; the results show which
+// item(s) failed.
+func BulkCreateTokens(c *gin.Context) {
+	var req BulkCreateTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, BulkCreateTokensResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if len(req.Tokens) == 0 {
+		c.JSON(http.StatusBadRequest, BulkCreateTokensResponse{Success: false, Message: "tokens must not be empty"})
+		return
+	}
+
+	var results []BulkCreateTokenResult
+	allOk := true
+
+	txErr := model.DB.Transaction(func(tx *gorm.DB) error {
+		results = make([]BulkCreateTokenResult, 0, len(req.Tokens))
+		for _, item := range req.Tokens {
+			result := createOneBulkToken(tx, item)
+			if !result.Success {
+				allOk = false
+			}
+			results = append(results, result)
+		}
+		if !allOk {
+			// Roll back: the whole batch is one transaction, so a
+			// failing item means nothing in this call is committed.
+			// Results are still returned so the caller can see which
+			// item(s) need fixing before retrying the batch.
+			return errBulkCreatePartialFailure
+		}
+		return nil
+	})
+	if txErr != nil && txErr != errBulkCreatePartialFailure {
+		c.JSON(http.StatusInternalServerError, BulkCreateTokensResponse{Success: false, Message: "Bulk creation failed: " + txErr.Error()})
+		return
+	}
+
+	message := "All tokens created successfully"
+	if !allOk {
+		message = "One or more tokens failed validation; the entire batch was rolled back, see results"
+		// Items that passed on their own were rolled back too, so their
+		// token ids and keys do not exist.
+		for i := range results {
+			if results[i].Success {
+				results[i] = BulkCreateTokenResult{Username: results[i].Username, Success: false, Message: "not created: batch rolled back"}
+			}
+		}
+	}
+	c.JSON(http.StatusOK, BulkCreateTokensResponse{Success: allOk, Message: message, Results: results})
+}
+
+// errBulkCreatePartialFailure is a sentinel used to roll back the
+// BulkCreateTokens transaction when any item fails, while still letting
+// the handler see the per-item results collected up to that point.
+var errBulkCreatePartialFailure = errors.New("bulk token creation: one or more items failed")
+
+func createOneBulkToken(tx *gorm.DB, item BulkCreateTokenItem) BulkCreateTokenResult {
+	if item.Username == "" {
+		return BulkCreateTokenResult{Username: item.Username, Success: false, Message: "username is required"}
+	}
+	user, err := model.GetUserByUsername(item.Username)
+	if err != nil {
+		return BulkCreateTokenResult{Username: item.Username, Success: false, Message: "user not found: " + err.Error()}
+	}
+
+	tokenName := item.TokenName
+	if tokenName == "" {
+		tokenName = "Auto-generated token"
+	}
+	var quotaGroup *model.QuotaGroup
+	remainQuota := item.RemainQuota
+	if item.QuotaGroup != "" {
+		quotaGroup, err = model.GetQuotaGroupByNameTx(tx, item.QuotaGroup)
+		if err != nil {
+			return BulkCreateTokenResult{Username: item.Username, Success: false, Message: "invalid quota_group: " + err.Error()}
+		}
+		if remainQuota <= 0 {
+			remainQuota = quotaGroupTotalQuota(quotaGroup)
+		}
+	}
+	if remainQuota <= 0 {
+		remainQuota = 100000
+	}
+	expiredTime := item.ExpiredTime
+	if expiredTime == 0 {
+		expiredTime = -1
+	}
+	group := item.Group
+	if group == "" {
+		group = "default"
+	}
+
+	key, err := common.GenerateKey()
+	if err != nil {
+		return BulkCreateTokenResult{Username: item.Username, Success: false, Message: "failed to generate API key: " + err.Error()}
+	}
+
+	token := model.Token{
+		UserId:       user.Id,
+		Name:         tokenName,
+		Key:          key,
+		CreatedTime:  common.GetTimestamp(),
+		AccessedTime: common.GetTimestamp(),
+		ExpiredTime:  expiredTime,
+		RemainQuota:  remainQuota,
+		Group:        group,
+	}
+	if err := token.InsertTx(tx); err != nil {
+		return BulkCreateTokenResult{Username: item.Username, Success: false, Message: "failed to create token: " + err.Error()}
+	}
+	if quotaGroup != nil {
+		if err := model.AttachQuotaGroupTx(tx, quotaGroup.Id, 0, token.Id); err != nil {
+			return BulkCreateTokenResult{Username: item.Username, Success: false, Message: "failed to attach quota group: " + err.Error()}
+		}
+	}
+
+	return BulkCreateTokenResult{Username: item.Username, Success: true, Message: "created", TokenID: token.Id, Key: key}
+}
+
+// RotateTokenRequest defines the request body for RotateToken.
+type RotateTokenRequest struct {
+	APIKey string `json:"api_key"`
+}
+
+// RotateTokenResponse defines the response body for RotateToken.
+type RotateTokenResponse struct {
+	Success        bool   `json:"success"`
+	Message        string `json:"message"`
+	OldKey         string `json:"old_key,omitempty"`
+	NewKey         string `json:"new_key,omitempty"`
+	GraceExpiresAt int64  `json:"grace_expires_at,omitempty"`
+}
+
+// RotateTokenGracePeriod is how long a rotated-out key keeps working
+// alongside its replacement. It is a package variable; no setting or
+// endpoint changes it.
+var RotateTokenGracePeriod int64 = 24 * 60 * 60 // 24h, in seconds
+
+// RotateToken issues a new key with the same settings/quota/group as an
+// existing token, marks the old key to expire after the grace period,
+// and returns both keys so in-flight callers don't break mid-rotation.
+func RotateToken(c *gin.Context) {
+	var req RotateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, RotateTokenResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.APIKey == "" {
+		c.JSON(http.StatusBadRequest, RotateTokenResponse{Success: false, Message: "api_key is required"})
+		return
+	}
+
+	oldToken, err := model.GetTokenByKey(req.APIKey, true)
+	if err != nil {
+		c.JSON(http.StatusNotFound, RotateTokenResponse{Success: false, Message: "Token not found: " + err.Error()})
+		return
+	}
+
+	newKey, err := common.GenerateKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, RotateTokenResponse{Success: false, Message: "Failed to generate API key: " + err.Error()})
+		return
+	}
+
+	newToken := model.Token{
+		UserId:       oldToken.UserId,
+		Name:         oldToken.Name,
+		Key:          newKey,
+		CreatedTime:  common.GetTimestamp(),
+		AccessedTime: common.GetTimestamp(),
+		ExpiredTime:  oldToken.ExpiredTime,
+		RemainQuota:  oldToken.RemainQuota,
+		Group:        oldToken.Group,
+
+		UnlimitedQuota:     oldToken.UnlimitedQuota,
+		ModelLimitsEnabled: oldToken.ModelLimitsEnabled,
+		ModelLimits:        oldToken.ModelLimits,
+		AllowIps:           oldToken.AllowIps,
+	}
+	if err := newToken.Insert(); err != nil {
+		c.JSON(http.StatusInternalServerError, RotateTokenResponse{Success: false, Message: "Failed to create rotated token: " + err.Error()})
+		return
+	}
+
+	graceExpiresAt := common.GetTimestamp() + RotateTokenGracePeriod
+	oldToken.GraceExpiresAt = graceExpiresAt
+	if err := oldToken.Update(); err != nil {
+		c.JSON(http.StatusInternalServerError, RotateTokenResponse{Success: false, Message: "Failed to mark old token for rotation: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, RotateTokenResponse{
+		Success:        true,
+		Message:        "Token rotated successfully",
+		OldKey:         oldToken.Key,
+		NewKey:         newKey,
+		GraceExpiresAt: graceExpiresAt,
+	})
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type jwtSettingsResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// UpdateJWTSettingsRequest defines the request body for configuring JWT
+// token issuance. SigningKey is the HS256 shared secret or, for RS256,
+// the PEM-encoded private key; PublicKey is required for RS256 and is
+// used only to verify, never to sign.
+type UpdateJWTSettingsRequest struct {
+	SigningKey               string `json:"signing_key"`
+	PublicKey                string `json:"public_key"`
+	Algorithm                string `json:"algorithm"`
+	Issuer                   string `json:"issuer"`
+	Audience                 string `json:"audience"`
+	CustomAccessTokenHookURL string `json:"custom_access_token_hook_url"`
+}
+
+// UpdateJWTSettings lets an admin configure JWT issuance (signing
+// key/algorithm/issuer/audience and the custom claims hook URL) without
+// editing source, and persists the change so it survives a restart.
+func UpdateJWTSettings(c *gin.Context) {
+	var req UpdateJWTSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, jwtSettingsResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.Algorithm == "RS256" && (req.SigningKey == "" || req.PublicKey == "") {
+		c.JSON(http.StatusBadRequest, jwtSettingsResponse{Success: false, Message: "RS256 requires both signing_key (private key) and public_key"})
+		return
+	}
+
+	settings := model.JWTSettings{
+		SigningKey:               req.SigningKey,
+		PublicKey:                req.PublicKey,
+		Algorithm:                req.Algorithm,
+		Issuer:                   req.Issuer,
+		Audience:                 req.Audience,
+		CustomAccessTokenHookURL: req.CustomAccessTokenHookURL,
+	}
+	if err := model.SaveJWTSettings(&settings); err != nil {
+		c.JSON(http.StatusInternalServerError, jwtSettingsResponse{Success: false, Message: "Failed to save JWT settings: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, jwtSettingsResponse{Success: true, Message: "JWT settings updated successfully"})
+}
+
+// GetJWTSettings returns the current JWT configuration. SigningKey is
+// redacted since it is a secret (or an RS256 private key).
+func GetJWTSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, jwtSettingsResponse{
+		Success: true,
+		Message: "",
+		Data: gin.H{
+			"signing_key_set": model.JWTSigningKey != "",
+			"public_key":      model.JWTPublicKey,
+			"algorithm":       model.JWTAlgorithm,
+			"issuer":          model.JWTIssuer,
+			"audience":        model.JWTAudience,
+			"custom_access_token_hook_url": model.CustomAccessTokenHookURL,
+		},
+	})
+}
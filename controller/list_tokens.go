@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenListItem is the shape ListTokens returns: every field of
+// model.Token an external dashboard or billing system needs to
+// reconcile state, minus the raw Key, which list responses never include.
+type TokenListItem struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	Status       int    `json:"status"`
+	Group        string `json:"group"`
+	RemainQuota  int    `json:"remain_quota"`
+	UsedQuota    int    `json:"used_quota"`
+	CreatedTime  int64  `json:"created_time"`
+	AccessedTime int64  `json:"accessed_time"`
+	ExpiredTime  int64  `json:"expired_time"`
+}
+
+// ListTokens returns a paged, filterable view of one user's tokens for
+// external admin dashboards and billing systems, so they don't have to
+// pull every row to reconcile state. Supported query params: user_id
+// (required), group, status, min_remain_quota, max_used_quota,
+// created_after, expires_before, name_contains, sort (e.g.
+// "-used_quota"), page, page_size (default 10, capped at 100).
+//
+// This route sits behind middleware.RequireProvisionerAuth, which proves
+// the caller holds a token:read-scoped provisioner key but, unlike a
+// dashboard session, carries no notion of "the caller's own user" — so
+// user_id must always be supplied explicitly. Defaulting it to 0 would
+// make the underlying filter a no-op and return every user's tokens to
+// any holder of a read-scoped key.
+func ListTokens(c *gin.Context) {
+	userId := queryInt(c, "user_id")
+	if userId <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "user_id is required",
+		})
+		return
+	}
+
+	filters := model.TokenFilters{
+		UserId:         userId,
+		Group:          c.Query("group"),
+		Status:         queryInt(c, "status"),
+		MinRemainQuota: queryInt(c, "min_remain_quota"),
+		MaxUsedQuota:   queryInt(c, "max_used_quota"),
+		CreatedAfter:   queryInt64(c, "created_after"),
+		ExpiresBefore:  queryInt64(c, "expires_before"),
+		NameContains:   c.Query("name_contains"),
+		Sort:           c.Query("sort"),
+		Page:           queryInt(c, "page"),
+		PageSize:       queryInt(c, "page_size"),
+	}
+
+	tokens, total, err := model.GetTokensByFilters(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to list tokens: " + err.Error(),
+		})
+		return
+	}
+
+	items := make([]TokenListItem, len(tokens))
+	for i, token := range tokens {
+		items[i] = TokenListItem{
+			Id:           token.Id,
+			Name:         token.Name,
+			Status:       token.Status,
+			Group:        token.Group,
+			RemainQuota:  token.RemainQuota,
+			UsedQuota:    token.UsedQuota,
+			CreatedTime:  token.CreatedTime,
+			AccessedTime: token.AccessedTime,
+			ExpiredTime:  token.ExpiredTime,
+		}
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    items,
+	})
+}
+
+func queryInt(c *gin.Context, name string) int {
+	v, err := strconv.Atoi(c.Query(name))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func queryInt64(c *gin.Context, name string) int64 {
+	v, err := strconv.ParseInt(c.Query(name), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
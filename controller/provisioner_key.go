@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"one-api/common"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type provisionerKeyResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// CreateProvisionerKeyRequest defines the request body for creating a
+// provisioner credential.
+type CreateProvisionerKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// CreateProvisionerKeyResponseData includes the secret exactly once, at
+// creation time; it is never returned by any other endpoint.
+type CreateProvisionerKeyResponseData struct {
+	Id     int    `json:"id"`
+	KeyId  string `json:"key_id"`
+	Secret string `json:"secret"`
+}
+
+// CreateProvisionerKey issues a new key_id/secret pair for machine-to-
+// machine provisioning (CI, a billing system, ...) scoped to the given
+// scopes, e.g. "token:create", "quota:write", "token:read".
+func CreateProvisionerKey(c *gin.Context) {
+	var req CreateProvisionerKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, provisionerKeyResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, provisionerKeyResponse{Success: false, Message: "name is required"})
+		return
+	}
+
+	keyId, err := common.GenerateKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, provisionerKeyResponse{Success: false, Message: "Failed to generate key_id: " + err.Error()})
+		return
+	}
+	secret, err := common.GenerateKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, provisionerKeyResponse{Success: false, Message: "Failed to generate secret: " + err.Error()})
+		return
+	}
+
+	scopes := ""
+	for i, scope := range req.Scopes {
+		if i > 0 {
+			scopes += ","
+		}
+		scopes += scope
+	}
+
+	provisionerKey := model.ProvisionerKey{
+		KeyId:  keyId,
+		Secret: secret,
+		Name:   req.Name,
+		Scopes: scopes,
+	}
+	if err := model.CreateProvisionerKey(&provisionerKey); err != nil {
+		c.JSON(http.StatusInternalServerError, provisionerKeyResponse{Success: false, Message: "Failed to create provisioner key: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, provisionerKeyResponse{
+		Success: true,
+		Message: "Provisioner key created successfully; store the secret now, it will not be shown again",
+		Data: CreateProvisionerKeyResponseData{
+			Id:     provisionerKey.Id,
+			KeyId:  provisionerKey.KeyId,
+			Secret: secret,
+		},
+	})
+}
+
+// ListProvisionerKeys returns every provisioner key's metadata (never
+// its secret).
+func ListProvisionerKeys(c *gin.Context) {
+	keys, err := model.GetAllProvisionerKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, provisionerKeyResponse{Success: false, Message: "Failed to list provisioner keys: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, provisionerKeyResponse{Success: true, Message: "", Data: keys})
+}
+
+// DisableProvisionerKey revokes a provisioner key so a compromised CI
+// credential can be cut off without touching any user's password.
+func DisableProvisionerKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, provisionerKeyResponse{Success: false, Message: "Invalid provisioner key id"})
+		return
+	}
+	key, err := model.GetProvisionerKeyById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, provisionerKeyResponse{Success: false, Message: "Provisioner key not found: " + err.Error()})
+		return
+	}
+	if err := key.Disable(); err != nil {
+		c.JSON(http.StatusInternalServerError, provisionerKeyResponse{Success: false, Message: "Failed to disable provisioner key: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, provisionerKeyResponse{Success: true, Message: "Provisioner key disabled successfully"})
+}
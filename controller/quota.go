@@ -0,0 +1,306 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// quotaResponse is the common envelope used by every /api/quota endpoint.
+type quotaResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// CreateQuotaRuleRequest defines the request body for creating a quota rule.
+type CreateQuotaRuleRequest struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Limit   int64  `json:"limit"`
+}
+
+// CreateQuotaRule creates a new quota rule, e.g. "100k total quota" or
+// "60 requests per minute".
+func CreateQuotaRule(c *gin.Context) {
+	var req CreateQuotaRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.Name == "" || req.Subject == "" {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "name and subject are required"})
+		return
+	}
+	rule := model.QuotaRule{Name: req.Name, Subject: req.Subject, Limit: req.Limit}
+	if err := model.CreateQuotaRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to create quota rule: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota rule created successfully", Data: rule})
+}
+
+// ListQuotaRules returns every quota rule.
+func ListQuotaRules(c *gin.Context) {
+	rules, err := model.GetAllQuotaRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to list quota rules: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "", Data: rules})
+}
+
+// UpdateQuotaRuleRequest defines the request body for updating a quota rule.
+type UpdateQuotaRuleRequest struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Limit   int64  `json:"limit"`
+}
+
+// UpdateQuotaRule patches an existing quota rule by id.
+func UpdateQuotaRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid rule id"})
+		return
+	}
+	rule, err := model.GetQuotaRuleById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, quotaResponse{Success: false, Message: "Quota rule not found: " + err.Error()})
+		return
+	}
+	var req UpdateQuotaRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.Subject != "" {
+		rule.Subject = req.Subject
+	}
+	if req.Limit != 0 {
+		rule.Limit = req.Limit
+	}
+	if err := rule.Update(); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to update quota rule: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota rule updated successfully", Data: rule})
+}
+
+// DeleteQuotaRule removes a quota rule by id.
+func DeleteQuotaRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid rule id"})
+		return
+	}
+	rule, err := model.GetQuotaRuleById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, quotaResponse{Success: false, Message: "Quota rule not found: " + err.Error()})
+		return
+	}
+	if err := rule.Delete(); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to delete quota rule: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota rule deleted successfully"})
+}
+
+// CreateQuotaGroupRequest defines the request body for creating a quota group.
+type CreateQuotaGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	RuleIds     []int  `json:"rule_ids"`
+}
+
+// CreateQuotaGroup bundles existing quota rules into a named group, e.g.
+// a "starter" group with 100k total quota and a 60 req/min ceiling.
+func CreateQuotaGroup(c *gin.Context) {
+	var req CreateQuotaGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "name is required"})
+		return
+	}
+	ids := make([]string, len(req.RuleIds))
+	for i, id := range req.RuleIds {
+		ids[i] = strconv.Itoa(id)
+	}
+	group := model.QuotaGroup{
+		Name:        req.Name,
+		Description: req.Description,
+		RuleIds:     strings.Join(ids, ","),
+	}
+	if err := model.CreateQuotaGroup(&group); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to create quota group: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota group created successfully", Data: group})
+}
+
+// ListQuotaGroups returns every quota group.
+func ListQuotaGroups(c *gin.Context) {
+	groups, err := model.GetAllQuotaGroups()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to list quota groups: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "", Data: groups})
+}
+
+// UpdateQuotaGroupRequest defines the request body for updating a quota group.
+type UpdateQuotaGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	RuleIds     []int  `json:"rule_ids"`
+}
+
+// UpdateQuotaGroup patches an existing quota group by id.
+func UpdateQuotaGroup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid group id"})
+		return
+	}
+	group, err := model.GetQuotaGroupById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, quotaResponse{Success: false, Message: "Quota group not found: " + err.Error()})
+		return
+	}
+	var req UpdateQuotaGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.Name != "" {
+		group.Name = req.Name
+	}
+	if req.Description != "" {
+		group.Description = req.Description
+	}
+	if req.RuleIds != nil {
+		ids := make([]string, len(req.RuleIds))
+		for i, ruleId := range req.RuleIds {
+			ids[i] = strconv.Itoa(ruleId)
+		}
+		group.RuleIds = strings.Join(ids, ",")
+	}
+	if err := group.Update(); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to update quota group: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota group updated successfully", Data: group})
+}
+
+// DeleteQuotaGroup removes a quota group and every mapping that attaches it.
+func DeleteQuotaGroup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid group id"})
+		return
+	}
+	group, err := model.GetQuotaGroupById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, quotaResponse{Success: false, Message: "Quota group not found: " + err.Error()})
+		return
+	}
+	if err := group.Delete(); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to delete quota group: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota group deleted successfully"})
+}
+
+// AttachQuotaGroupRequest defines the request body for binding a quota
+// group to a user or a token.
+type AttachQuotaGroupRequest struct {
+	QuotaGroupId int `json:"quota_group_id"`
+	UserId       int `json:"user_id"`
+	TokenId      int `json:"token_id"`
+}
+
+// AttachQuotaGroup binds a quota group to a user or a token.
+func AttachQuotaGroup(c *gin.Context) {
+	var req AttachQuotaGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.QuotaGroupId <= 0 {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "quota_group_id is required"})
+		return
+	}
+	if err := model.AttachQuotaGroup(req.QuotaGroupId, req.UserId, req.TokenId); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to attach quota group: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota group attached successfully"})
+}
+
+// DetachQuotaGroup unbinds a quota group from a user or a token.
+func DetachQuotaGroup(c *gin.Context) {
+	var req AttachQuotaGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid request parameters: " + err.Error()})
+		return
+	}
+	if req.QuotaGroupId <= 0 {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "quota_group_id is required"})
+		return
+	}
+	if err := model.DetachQuotaGroup(req.QuotaGroupId, req.UserId, req.TokenId); err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to detach quota group: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "Quota group detached successfully"})
+}
+
+// CheckQuota reports used/limit for a subject on a given token, mirroring
+// Forgejo's CheckQuota so external systems can preflight before making calls.
+func CheckQuota(c *gin.Context) {
+	subject := c.Query("subject")
+	if subject == "" {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "subject is required"})
+		return
+	}
+	tokenId, err := strconv.Atoi(c.Query("token_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, quotaResponse{Success: false, Message: "Invalid token_id"})
+		return
+	}
+	token, err := model.GetTokenById(tokenId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, quotaResponse{Success: false, Message: "Token not found: " + err.Error()})
+		return
+	}
+	used := int64(token.UsedQuota)
+	if subject != model.QuotaSubjectTotalQuota {
+		// Other subjects are counted in the QuotaUsageCounter rows that
+		// QuotaGroupEnforce increments; requests_per_minute per minute.
+		var windowStart int64
+		if subject == model.QuotaSubjectRequestsPerMin {
+			windowStart = model.CurrentWindowStart(60)
+		}
+		var counter model.QuotaUsageCounter
+		used = 0
+		if err := model.DB.Where("token_id = ? AND subject = ? AND window_start = ?", token.Id, subject, windowStart).First(&counter).Error; err == nil {
+			used = counter.Count
+		}
+	}
+	usage, err := model.CheckQuotaUsage(token.Id, subject, used)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, quotaResponse{Success: false, Message: "Failed to check quota: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotaResponse{Success: true, Message: "", Data: usage})
+}
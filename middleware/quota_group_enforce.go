@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestsPerMinuteWindow is the width, in seconds, of the fixed,
+// clock-aligned window requests_per_minute counts against.
+const requestsPerMinuteWindow = 60
+
+// QuotaGroupEnforce sits in the relay/consumption chain, after the
+// token has been resolved (TokenAuth or JWTAuth has already set
+// "token_id" in the context) and before the request is actually
+// relayed. It rejects the request once any subject of any quota group
+// mapped to the token is over its limit, so groups set up via
+// /api/quota are actually enforced rather than only queryable through
+// /api/quota/check. Four subjects are covered:
+//   - total_quota: checked against Token.UsedQuota, which is already
+//     maintained by the existing consumption path.
+//   - requests_per_minute: checked pre-request against a counter this
+//     middleware maintains itself.
+//   - image_generation_count: checked pre-request, same as above, but
+//     only incremented for requests that look like an image generation
+//     call.
+//   - tokens_per_model:<model>: can only be known once the relay
+//     handler has actually counted completion tokens, so it is checked
+//     after the request completes, from completion_model/
+//     completion_tokens_used context values the relay handler is
+//     expected to set; a request that turns out to be over limit can't
+//     be un-sent, but the next one against that token+model is rejected.
+func QuotaGroupEnforce() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenId, ok := c.Get("token_id")
+		if !ok {
+			c.Next()
+			return
+		}
+		id, ok := tokenId.(int)
+		if !ok || id <= 0 {
+			c.Next()
+			return
+		}
+
+		token, err := model.GetTokenById(id)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		if err := model.EnforceQuotaGroups(id, model.QuotaSubjectTotalQuota, int64(token.UsedQuota)); err != nil {
+			rejectForQuotaGroup(c, err)
+			return
+		}
+
+		requestsThisMinute, err := model.IncrementQuotaUsage(id, model.QuotaSubjectRequestsPerMin, model.CurrentWindowStart(requestsPerMinuteWindow), 1)
+		if err == nil {
+			if err := model.EnforceQuotaGroups(id, model.QuotaSubjectRequestsPerMin, requestsThisMinute); err != nil {
+				rejectForQuotaGroup(c, err)
+				return
+			}
+		}
+
+		if looksLikeImageGenerationRequest(c.Request.URL.Path) {
+			imagesGenerated, err := model.IncrementQuotaUsage(id, model.QuotaSubjectImageGeneration, 0, 1)
+			if err == nil {
+				if err := model.EnforceQuotaGroups(id, model.QuotaSubjectImageGeneration, imagesGenerated); err != nil {
+					rejectForQuotaGroup(c, err)
+					return
+				}
+			}
+		}
+
+		c.Next()
+
+		enforceTokensPerModelAfterResponse(c, id)
+	}
+}
+
+func looksLikeImageGenerationRequest(path string) bool {
+	return strings.Contains(path, "/images/generations")
+}
+
+func enforceTokensPerModelAfterResponse(c *gin.Context, tokenId int) {
+	modelName, hasModel := c.Get("completion_model")
+	tokensUsed, hasTokens := c.Get("completion_tokens_used")
+	if !hasModel || !hasTokens {
+		return
+	}
+	modelStr, ok := modelName.(string)
+	if !ok || modelStr == "" {
+		return
+	}
+	tokensInt, ok := tokensUsed.(int64)
+	if !ok || tokensInt <= 0 {
+		return
+	}
+
+	subject := model.QuotaSubjectTokensPerModel + ":" + modelStr
+	total, err := model.IncrementQuotaUsage(tokenId, subject, 0, tokensInt)
+	if err != nil {
+		return
+	}
+	// The response for this request has already been written; an
+	// over-limit result here only rejects the *next* request against
+	// this token+model, same as any other post-hoc usage accounting.
+	_ = model.EnforceQuotaGroups(tokenId, subject, total)
+}
+
+func rejectForQuotaGroup(c *gin.Context, err error) {
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error": gin.H{"message": err.Error(), "type": "one_api_quota_group_error"},
+	})
+	c.Abort()
+}
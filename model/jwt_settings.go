@@ -0,0 +1,70 @@
+package model
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// JWTSettings persists the system-wide configuration that controls JWT
+// token issuance and verification. The in-memory vars in
+// system_setting.go are what the signing/verification code actually
+// reads; this table is what makes those vars survive a restart and be
+// configurable through the admin API instead of only by editing source.
+type JWTSettings struct {
+	Id                       int    `json:"id"`
+	SigningKey               string `json:"signing_key"`
+	PublicKey                string `json:"public_key"`
+	Algorithm                string `json:"algorithm"`
+	Issuer                   string `json:"issuer"`
+	Audience                 string `json:"audience"`
+	CustomAccessTokenHookURL string `json:"custom_access_token_hook_url"`
+}
+
+// jwtSettingsSingletonId is the row JWTSettings always lives at; there
+// is only ever one configuration, so it is not exposed for lookup by id.
+const jwtSettingsSingletonId = 1
+
+// LoadJWTSettings reads the persisted JWT configuration, if any, into
+// the in-memory vars read by the signing/verification code. Call once
+// at startup, after InitDB. A missing row (fresh install) is not an
+// error; the defaults in system_setting.go apply instead.
+func LoadJWTSettings() error {
+	var settings JWTSettings
+	err := DB.First(&settings, "id = ?", jwtSettingsSingletonId).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	applyJWTSettings(&settings)
+	return nil
+}
+
+// SaveJWTSettings upserts the JWT configuration and applies it to the
+// in-memory vars immediately, so an admin's change takes effect without
+// a restart.
+func SaveJWTSettings(settings *JWTSettings) error {
+	settings.Id = jwtSettingsSingletonId
+	if err := DB.Save(settings).Error; err != nil {
+		return err
+	}
+	applyJWTSettings(settings)
+	return nil
+}
+
+func applyJWTSettings(settings *JWTSettings) {
+	JWTSigningKey = settings.SigningKey
+	JWTPublicKey = settings.PublicKey
+	if settings.Algorithm != "" {
+		JWTAlgorithm = settings.Algorithm
+	}
+	if settings.Issuer != "" {
+		JWTIssuer = settings.Issuer
+	}
+	if settings.Audience != "" {
+		JWTAudience = settings.Audience
+	}
+	CustomAccessTokenHookURL = settings.CustomAccessTokenHookURL
+}
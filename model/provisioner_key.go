@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"one-api/common"
+)
+
+// Provisioner key scopes. A key only authorizes the endpoints matching
+// its Scopes, so a compromised CI key can be revoked without touching
+// user passwords or other provisioners.
+const (
+	ProvisionerScopeTokenCreate = "token:create"
+	ProvisionerScopeQuotaWrite  = "quota:write"
+	ProvisionerScopeTokenRead   = "token:read"
+)
+
+// ProvisionerKey is a machine-to-machine credential used to authenticate
+// the provisioning endpoints mounted by router.SetupProvisioningRouter
+// (AutoCreateToken, BulkCreateTokens, RotateToken, ListTokens) via HMAC
+// request signing instead of a user's username/password.
+// UpdateTokenQuota, UpdateTokenQuotaByKey and AddTokenQuota are not
+// mounted behind it.
+type ProvisionerKey struct {
+	Id          int    `json:"id"`
+	KeyId       string `json:"key_id" gorm:"uniqueIndex"`
+	Secret      string `json:"-"` // never serialized back to clients
+	Name        string `json:"name"`
+	Scopes      string `json:"scopes"` // comma-separated, e.g. "token:create,quota:write"
+	Disabled    bool   `json:"disabled"`
+	CreatedTime int64  `json:"created_time" gorm:"bigint"`
+}
+
+func CreateProvisionerKey(key *ProvisionerKey) error {
+	key.CreatedTime = common.GetTimestamp()
+	return DB.Create(key).Error
+}
+
+func GetProvisionerKeyByKeyId(keyId string) (*ProvisionerKey, error) {
+	if keyId == "" {
+		return nil, errors.New("key_id is empty")
+	}
+	var key ProvisionerKey
+	err := DB.First(&key, "key_id = ?", keyId).Error
+	return &key, err
+}
+
+func GetProvisionerKeyById(id int) (*ProvisionerKey, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid provisioner key id")
+	}
+	var key ProvisionerKey
+	err := DB.First(&key, "id = ?", id).Error
+	return &key, err
+}
+
+func GetAllProvisionerKeys() ([]*ProvisionerKey, error) {
+	var keys []*ProvisionerKey
+	err := DB.Order("id desc").Find(&keys).Error
+	return keys, err
+}
+
+func (key *ProvisionerKey) Disable() error {
+	key.Disabled = true
+	return DB.Model(key).Update("disabled", true).Error
+}
+
+func (key *ProvisionerKey) HasScope(scope string) bool {
+	for _, s := range strings.Split(key.Scopes, ",") {
+		if strings.TrimSpace(s) == scope {
+			return true
+		}
+	}
+	return false
+}
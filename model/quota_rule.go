@@ -0,0 +1,259 @@
+package model
+
+import (
+	"errors"
+	"one-api/common"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Quota rule subjects. A rule caps one measurable dimension of usage;
+// a QuotaGroup bundles several rules together so they can be assigned
+// to a user or token as a single named policy.
+const (
+	QuotaSubjectTotalQuota       = "total_quota"
+	QuotaSubjectRequestsPerMin   = "requests_per_minute"
+	QuotaSubjectTokensPerModel   = "tokens_per_model" // suffixed, e.g. "tokens_per_model:gpt-4"
+	QuotaSubjectImageGeneration  = "image_generation_count"
+)
+
+// QuotaRule caps a single subject (e.g. total_quota, requests_per_minute)
+// at Limit. Subject may carry a ":"-separated qualifier such as
+// "tokens_per_model:gpt-4" to scope the rule to one model.
+type QuotaRule struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name" gorm:"index"`
+	Subject     string `json:"subject"`
+	Limit       int64  `json:"limit"`
+	CreatedTime int64  `json:"created_time" gorm:"bigint"`
+}
+
+// QuotaGroup is a named bundle of QuotaRules that can be attached to a
+// user or token via QuotaGroupMapping.
+type QuotaGroup struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name" gorm:"uniqueIndex"`
+	Description string `json:"description"`
+	RuleIds     string `json:"-" gorm:"type:text"` // comma-separated QuotaRule ids
+	CreatedTime int64  `json:"created_time" gorm:"bigint"`
+}
+
+// QuotaGroupMapping binds a QuotaGroup to either a user or a token.
+// Exactly one of UserId / TokenId should be set.
+type QuotaGroupMapping struct {
+	Id           int   `json:"id"`
+	QuotaGroupId int   `json:"quota_group_id" gorm:"index"`
+	UserId       int   `json:"user_id" gorm:"index"`
+	TokenId      int   `json:"token_id" gorm:"index"`
+	CreatedTime  int64 `json:"created_time" gorm:"bigint"`
+}
+
+func CreateQuotaRule(rule *QuotaRule) error {
+	rule.CreatedTime = common.GetTimestamp()
+	return DB.Create(rule).Error
+}
+
+func GetQuotaRuleById(id int) (*QuotaRule, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid quota rule id")
+	}
+	rule := QuotaRule{Id: id}
+	err := DB.First(&rule, "id = ?", id).Error
+	return &rule, err
+}
+
+func GetAllQuotaRules() ([]*QuotaRule, error) {
+	var rules []*QuotaRule
+	err := DB.Order("id desc").Find(&rules).Error
+	return rules, err
+}
+
+func (rule *QuotaRule) Update() error {
+	return DB.Model(rule).Updates(rule).Error
+}
+
+func (rule *QuotaRule) Delete() error {
+	return DB.Delete(rule).Error
+}
+
+func CreateQuotaGroup(group *QuotaGroup) error {
+	group.CreatedTime = common.GetTimestamp()
+	return DB.Create(group).Error
+}
+
+func GetQuotaGroupById(id int) (*QuotaGroup, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid quota group id")
+	}
+	group := QuotaGroup{Id: id}
+	err := DB.First(&group, "id = ?", id).Error
+	return &group, err
+}
+
+func GetQuotaGroupByName(name string) (*QuotaGroup, error) {
+	return getQuotaGroupByName(DB, name)
+}
+
+// GetQuotaGroupByNameTx is GetQuotaGroupByName scoped to an in-progress
+// transaction, so callers like BulkCreateTokens can resolve a group
+// without escaping the transaction.
+func GetQuotaGroupByNameTx(tx *gorm.DB, name string) (*QuotaGroup, error) {
+	return getQuotaGroupByName(tx, name)
+}
+
+func getQuotaGroupByName(tx *gorm.DB, name string) (*QuotaGroup, error) {
+	if name == "" {
+		return nil, errors.New("quota group name is empty")
+	}
+	var group QuotaGroup
+	err := tx.First(&group, "name = ?", name).Error
+	return &group, err
+}
+
+func GetAllQuotaGroups() ([]*QuotaGroup, error) {
+	var groups []*QuotaGroup
+	err := DB.Order("id desc").Find(&groups).Error
+	return groups, err
+}
+
+func (group *QuotaGroup) Update() error {
+	return DB.Model(group).Updates(group).Error
+}
+
+func (group *QuotaGroup) Delete() error {
+	if err := DB.Where("quota_group_id = ?", group.Id).Delete(&QuotaGroupMapping{}).Error; err != nil {
+		return err
+	}
+	return DB.Delete(group).Error
+}
+
+// AttachQuotaGroup binds groupId to a user or a token. Exactly one of
+// userId / tokenId should be non-zero.
+func AttachQuotaGroup(groupId, userId, tokenId int) error {
+	return attachQuotaGroup(DB, groupId, userId, tokenId)
+}
+
+// AttachQuotaGroupTx is AttachQuotaGroup scoped to an in-progress
+// transaction.
+func AttachQuotaGroupTx(tx *gorm.DB, groupId, userId, tokenId int) error {
+	return attachQuotaGroup(tx, groupId, userId, tokenId)
+}
+
+func attachQuotaGroup(tx *gorm.DB, groupId, userId, tokenId int) error {
+	if userId == 0 && tokenId == 0 {
+		return errors.New("either user_id or token_id must be provided")
+	}
+	mapping := QuotaGroupMapping{
+		QuotaGroupId: groupId,
+		UserId:       userId,
+		TokenId:      tokenId,
+		CreatedTime:  common.GetTimestamp(),
+	}
+	return tx.Create(&mapping).Error
+}
+
+func DetachQuotaGroup(groupId, userId, tokenId int) error {
+	query := DB.Where("quota_group_id = ?", groupId)
+	if userId != 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+	if tokenId != 0 {
+		query = query.Where("token_id = ?", tokenId)
+	}
+	return query.Delete(&QuotaGroupMapping{}).Error
+}
+
+func GetQuotaGroupsForToken(tokenId int) ([]*QuotaGroup, error) {
+	var groups []*QuotaGroup
+	err := DB.Table("quota_groups").
+		Joins("join quota_group_mappings on quota_group_mappings.quota_group_id = quota_groups.id").
+		Where("quota_group_mappings.token_id = ?", tokenId).
+		Find(&groups).Error
+	return groups, err
+}
+
+// QuotaUsage reports how much of a subject's limit has been consumed,
+// mirroring Forgejo's CheckQuota output shape.
+type QuotaUsage struct {
+	Subject string `json:"subject"`
+	Used    int64  `json:"used"`
+	Limit   int64  `json:"limit"`
+	Ok      bool   `json:"ok"`
+}
+
+// CheckQuotaUsage looks up every rule matching subject across the quota
+// groups attached to tokenId and returns the tightest limit together
+// with the current usage. Callers in the consumption path should treat
+// Ok == false as a hard rejection.
+func CheckQuotaUsage(tokenId int, subject string, used int64) (*QuotaUsage, error) {
+	groups, err := GetQuotaGroupsForToken(tokenId)
+	if err != nil {
+		return nil, err
+	}
+	usage := &QuotaUsage{Subject: subject, Used: used, Limit: -1, Ok: true}
+	for _, group := range groups {
+		rules, err := rulesForGroup(group)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			if rule.Subject != subject {
+				continue
+			}
+			if usage.Limit == -1 || rule.Limit < usage.Limit {
+				usage.Limit = rule.Limit
+			}
+		}
+	}
+	if usage.Limit != -1 && used > usage.Limit {
+		usage.Ok = false
+	}
+	return usage, nil
+}
+
+// ErrQuotaGroupExceeded is returned by EnforceQuotaGroups when a subject
+// is over its mapped limit; callers should reject the request with it.
+var ErrQuotaGroupExceeded = errors.New("quota group limit exceeded")
+
+// EnforceQuotaGroups is the hook the request-consumption path calls so a
+// request is rejected once any subject in any group mapped to the token
+// exceeds its limit. middleware.QuotaGroupEnforce calls it before
+// relaying for total_quota (used is Token.UsedQuota, not counting the
+// request being evaluated), requests_per_minute and
+// image_generation_count (used already counts this request), and after
+// the response for tokens_per_model.
+func EnforceQuotaGroups(tokenId int, subject string, used int64) error {
+	usage, err := CheckQuotaUsage(tokenId, subject, used)
+	if err != nil {
+		return err
+	}
+	if !usage.Ok {
+		return ErrQuotaGroupExceeded
+	}
+	return nil
+}
+
+func rulesForGroup(group *QuotaGroup) ([]*QuotaRule, error) {
+	var rules []*QuotaRule
+	if group.RuleIds == "" {
+		return rules, nil
+	}
+	ids := make([]int, 0)
+	for _, part := range strings.Split(group.RuleIds, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return rules, nil
+	}
+	err := DB.Where("id IN (?)", ids).Find(&rules).Error
+	if err == gorm.ErrRecordNotFound {
+		return rules, nil
+	}
+	return rules, err
+}
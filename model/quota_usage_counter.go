@@ -0,0 +1,56 @@
+package model
+
+import (
+	"one-api/common"
+
+	"gorm.io/gorm"
+)
+
+// QuotaUsageCounter tracks cumulative usage for quota subjects that
+// aren't already tracked on Token itself (total_quota uses
+// Token.UsedQuota directly). WindowStart is 0 for subjects with no
+// time window (tokens_per_model, image_generation_count) and the
+// start of the current window for rate-style subjects
+// (requests_per_minute), so the same row keeps accumulating until the
+// window rolls over.
+type QuotaUsageCounter struct {
+	Id          int    `json:"id"`
+	TokenId     int    `json:"token_id" gorm:"uniqueIndex:idx_quota_usage_counter"`
+	Subject     string `json:"subject" gorm:"uniqueIndex:idx_quota_usage_counter"`
+	WindowStart int64  `json:"window_start" gorm:"uniqueIndex:idx_quota_usage_counter"`
+	Count       int64  `json:"count"`
+}
+
+// IncrementQuotaUsage adds amount to tokenId's running count for subject
+// within the window starting at windowStart (pass 0 for subjects with no
+// window) and returns the count after the increment, so callers can feed
+// the result straight into EnforceQuotaGroups.
+func IncrementQuotaUsage(tokenId int, subject string, windowStart int64, amount int64) (int64, error) {
+	var counter QuotaUsageCounter
+	err := DB.Where("token_id = ? AND subject = ? AND window_start = ?", tokenId, subject, windowStart).First(&counter).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return 0, err
+		}
+		counter = QuotaUsageCounter{TokenId: tokenId, Subject: subject, WindowStart: windowStart, Count: amount}
+		if err := DB.Create(&counter).Error; err != nil {
+			return 0, err
+		}
+		return counter.Count, nil
+	}
+	if err := DB.Model(&counter).Update("count", gorm.Expr("count + ?", amount)).Error; err != nil {
+		return 0, err
+	}
+	return counter.Count + amount, nil
+}
+
+// CurrentWindowStart truncates the current time to the start of a
+// windowSeconds-wide bucket, e.g. 60 for a per-minute window. A
+// non-positive windowSeconds means "no window" (always bucket 0).
+func CurrentWindowStart(windowSeconds int64) int64 {
+	if windowSeconds <= 0 {
+		return 0
+	}
+	now := common.GetTimestamp()
+	return now - (now % windowSeconds)
+}
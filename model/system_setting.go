@@ -0,0 +1,21 @@
+package model
+
+// System settings that control JWT token issuance. These are read by
+// controller.AutoCreateToken when a caller requests token_type "jwt" and
+// by the JWT verification middleware on the relay path. They default to
+// the values below but are overwritten at startup by LoadJWTSettings
+// and whenever an admin calls SaveJWTSettings (see jwt_settings.go), so
+// operators can configure JWT issuance without editing source.
+var (
+	JWTSigningKey            = "" // HS256 shared secret, or RS256 private key PEM
+	JWTPublicKey             = "" // RS256 public key PEM, used for verification only
+	JWTAlgorithm             = "HS256" // HS256 or RS256
+	JWTIssuer                = "one-api"
+	JWTAudience              = "one-api-relay"
+	CustomAccessTokenHookURL = ""
+)
+
+// ProvisionerAuthEnabled gates middleware.ProvisionerAuth: off by default
+// so existing username/password-authenticated provisioning keeps
+// working, an operator opts in once they've issued provisioner keys.
+var ProvisionerAuthEnabled = false
@@ -0,0 +1,101 @@
+package model
+
+// TokenFilters narrows a ListTokens query. Zero-valued fields are
+// ignored. Sort is a column name, optionally prefixed with "-" for
+// descending order (e.g. "-used_quota").
+type TokenFilters struct {
+	UserId         int
+	Group          string
+	Status         int
+	MinRemainQuota int
+	MaxUsedQuota   int
+	CreatedAfter   int64
+	ExpiresBefore  int64
+	NameContains   string
+	Sort           string
+	Page           int
+	PageSize       int
+}
+
+var tokenSortableColumns = map[string]bool{
+	"id":            true,
+	"created_time":  true,
+	"remain_quota":  true,
+	"used_quota":    true,
+	"expired_time":  true,
+	"name":          true,
+}
+
+// GetTokensByFilters returns the page of tokens matching f along with the
+// total row count across all pages, so callers can reconcile the token
+// table without pulling it in full.
+func GetTokensByFilters(f TokenFilters) ([]*Token, int64, error) {
+	query := DB.Model(&Token{})
+
+	if f.UserId > 0 {
+		query = query.Where("user_id = ?", f.UserId)
+	}
+	if f.Group != "" {
+		query = query.Where("`group` = ?", f.Group)
+	}
+	if f.Status > 0 {
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.MinRemainQuota > 0 {
+		query = query.Where("remain_quota >= ?", f.MinRemainQuota)
+	}
+	if f.MaxUsedQuota > 0 {
+		query = query.Where("used_quota <= ?", f.MaxUsedQuota)
+	}
+	if f.CreatedAfter > 0 {
+		query = query.Where("created_time >= ?", f.CreatedAfter)
+	}
+	if f.ExpiresBefore > 0 {
+		query = query.Where("expired_time > 0 AND expired_time <= ?", f.ExpiresBefore)
+	}
+	if f.NameContains != "" {
+		query = query.Where("name LIKE ?", "%"+f.NameContains+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := sortClause(f.Sort)
+	if order != "" {
+		query = query.Order(order)
+	}
+
+	page := f.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := f.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var tokens []*Token
+	err := query.Offset((page - 1) * pageSize).Limit(pageSize).Find(&tokens).Error
+	return tokens, total, err
+}
+
+func sortClause(sort string) string {
+	if sort == "" {
+		return "id desc"
+	}
+	direction := "asc"
+	column := sort
+	if column[0] == '-' {
+		direction = "desc"
+		column = column[1:]
+	}
+	if !tokenSortableColumns[column] {
+		return "id desc"
+	}
+	return column + " " + direction
+}
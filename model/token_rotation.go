@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"one-api/common"
+)
+
+// tokenRotationSweepInterval is how often StartTokenRotationSweeper
+// checks for keys whose grace period has elapsed.
+const tokenRotationSweepInterval = 5 * time.Minute
+
+// StartTokenRotationSweeper launches a background goroutine that
+// disables rotated-out tokens once their grace period elapses. Call
+// once at startup, alongside the other background jobs.
+func StartTokenRotationSweeper() {
+	go func() {
+		ticker := time.NewTicker(tokenRotationSweepInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sweepExpiredRotations()
+		}
+	}()
+}
+
+func sweepExpiredRotations() {
+	now := common.GetTimestamp()
+	var expired []*Token
+	err := DB.Where("grace_expires_at > 0 AND grace_expires_at <= ?", now).Find(&expired).Error
+	if err != nil {
+		common.SysError("token rotation sweeper: failed to query expired keys: " + err.Error())
+		return
+	}
+	for _, token := range expired {
+		token.Status = TokenStatusDisabled
+		token.GraceExpiresAt = 0
+		if err := token.Update(); err != nil {
+			common.SysError("token rotation sweeper: failed to disable token " + token.Key + ": " + err.Error())
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package model
+
+import "gorm.io/gorm"
+
+// InsertTx is Insert but scoped to an in-progress transaction, for
+// callers (e.g. BulkCreateTokens) that need every row to commit or
+// roll back together.
+func (token *Token) InsertTx(tx *gorm.DB) error {
+	return tx.Create(token).Error
+}
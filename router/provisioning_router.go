@@ -0,0 +1,57 @@
+package router
+
+import (
+	"one-api/controller"
+	"one-api/middleware"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupProvisioningRouter mounts the machine-to-machine token
+// provisioning endpoints (auto-create, bulk create, rotate, token
+// listing, provisioner keys) under /api, and starts the background jobs
+// those endpoints depend on. Call this alongside the other
+// Setup*Router functions when assembling the gin engine, after
+// model.InitDB has already run.
+//
+// /token/auto runs behind middleware.ProvisionerAuth, which is a
+// pass-through until an operator sets model.ProvisionerAuthEnabled: it
+// already authenticates callers via username/password, so the
+// provisioner signature is an opt-in extra, not its only guard.
+// /token/bulk and /token/rotate have no credential of their own, so they
+// run behind middleware.RequireProvisionerAuth instead, which is never a
+// pass-through. /api/provisioner/keys mints and disables the provisioner
+// keys those two rely on, so it is admin-only (middleware.AdminAuth) —
+// otherwise anyone could self-issue a fully-scoped key and bypass the
+// signature check entirely.
+func SetupProvisioningRouter(router *gin.Engine) {
+	autoRouter := router.Group("/api")
+	autoRouter.Use(middleware.ProvisionerAuth(model.ProvisionerScopeTokenCreate))
+	{
+		autoRouter.POST("/token/auto", controller.AutoCreateToken)
+	}
+
+	bulkRouter := router.Group("/api")
+	bulkRouter.Use(middleware.RequireProvisionerAuth(model.ProvisionerScopeTokenCreate))
+	{
+		bulkRouter.POST("/token/bulk", controller.BulkCreateTokens)
+		bulkRouter.POST("/token/rotate", controller.RotateToken)
+	}
+
+	readRouter := router.Group("/api")
+	readRouter.Use(middleware.RequireProvisionerAuth(model.ProvisionerScopeTokenRead))
+	{
+		readRouter.GET("/token/list", controller.ListTokens)
+	}
+
+	provisionerKeyRouter := router.Group("/api/provisioner")
+	provisionerKeyRouter.Use(middleware.AdminAuth())
+	{
+		provisionerKeyRouter.POST("/keys", controller.CreateProvisionerKey)
+		provisionerKeyRouter.GET("/keys", controller.ListProvisionerKeys)
+		provisionerKeyRouter.POST("/keys/:id/disable", controller.DisableProvisionerKey)
+	}
+
+	model.StartTokenRotationSweeper()
+}
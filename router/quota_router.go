@@ -0,0 +1,55 @@
+package router
+
+import (
+	"one-api/common"
+	"one-api/controller"
+	"one-api/middleware"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupQuotaRouter mounts the quota rule/group admin endpoints under
+// /api/quota, behind middleware.AdminAuth() — these create and attach
+// the same limits QuotaGroupEnforce checks on the consumption path, so
+// leaving them open would let any caller raise or remove their own
+// quota. Call this alongside the other Setup*Router functions.
+func SetupQuotaRouter(router *gin.Engine) {
+	quotaRouter := router.Group("/api/quota")
+	quotaRouter.Use(middleware.AdminAuth())
+	{
+		quotaRouter.POST("/rules", controller.CreateQuotaRule)
+		quotaRouter.GET("/rules", controller.ListQuotaRules)
+		quotaRouter.PATCH("/rules/:id", controller.UpdateQuotaRule)
+		quotaRouter.DELETE("/rules/:id", controller.DeleteQuotaRule)
+
+		quotaRouter.POST("/groups", controller.CreateQuotaGroup)
+		quotaRouter.GET("/groups", controller.ListQuotaGroups)
+		quotaRouter.PATCH("/groups/:id", controller.UpdateQuotaGroup)
+		quotaRouter.DELETE("/groups/:id", controller.DeleteQuotaGroup)
+
+		quotaRouter.POST("/groups/attach", controller.AttachQuotaGroup)
+		quotaRouter.POST("/groups/detach", controller.DetachQuotaGroup)
+
+		quotaRouter.GET("/check", controller.CheckQuota)
+	}
+}
+
+// SetupJWTSettingsRouter mounts the admin endpoints used to configure
+// JWT token issuance (signing key/algorithm/issuer/audience, custom
+// access token hook URL) so operators can set them without editing
+// source. Behind middleware.AdminAuth() — an unauthenticated caller able
+// to rewrite JWTSigningKey/JWTAlgorithm could forge a valid token for
+// any token_id, same as leaving the key unset in the first place.
+func SetupJWTSettingsRouter(router *gin.Engine) {
+	jwtRouter := router.Group("/api/jwt/settings")
+	jwtRouter.Use(middleware.AdminAuth())
+	{
+		jwtRouter.GET("", controller.GetJWTSettings)
+		jwtRouter.POST("", controller.UpdateJWTSettings)
+	}
+
+	if err := model.LoadJWTSettings(); err != nil {
+		common.SysError("failed to load persisted JWT settings: " + err.Error())
+	}
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"one-api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RelayAuthMiddlewares returns the middleware chain the relay routes
+// must run behind the existing TokenAuth: JWTAuth so JWT-issued tokens
+// (chunk0-2) short-circuit the opaque-key lookup, then QuotaGroupEnforce
+// so a request is rejected once the token's quota groups (chunk0-1) are
+// over limit. Append the result to the existing relay route group's
+// middleware list, after TokenAuth.
+func RelayAuthMiddlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		middleware.JWTAuth(),
+		middleware.QuotaGroupEnforce(),
+	}
+}